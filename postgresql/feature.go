@@ -0,0 +1,34 @@
+package postgresql
+
+import "database/sql"
+
+// featureName identifies an optional capability that's only available on
+// certain PostgreSQL server versions, so resources can gate behavior on it
+// instead of letting the server reject an unsupported statement.
+type featureName int
+
+const (
+	// featureProcedure covers CREATE/ALTER/GRANT ... PROCEDURE, introduced
+	// in PostgreSQL 11.
+	featureProcedure featureName = iota
+)
+
+var featureMinimumVersion = map[featureName]int{
+	featureProcedure: 110000,
+}
+
+// featureSupported reports whether feature is available on the server conn
+// is connected to.
+func featureSupported(conn *sql.DB, feature featureName) (bool, error) {
+	minVersion, ok := featureMinimumVersion[feature]
+	if !ok {
+		return false, nil
+	}
+
+	var serverVersion int
+	if err := conn.QueryRow("SHOW server_version_num").Scan(&serverVersion); err != nil {
+		return false, err
+	}
+
+	return serverVersion >= minVersion, nil
+}
@@ -0,0 +1,321 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/lib/pq"
+)
+
+// defaultPrivilegesObjectTypes maps the object_type values this resource
+// accepts to the single-letter code PostgreSQL stores in
+// pg_default_acl.defaclobjtype.
+var defaultPrivilegesObjectTypes = map[string]string{
+	"table":    "r",
+	"sequence": "S",
+	"function": "f",
+	"type":     "T",
+}
+
+var validDefaultPrivilegesObjectTypes = []string{"table", "sequence", "function", "type"}
+
+func resourcePostgreSQLDefaultPrivileges() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePostgreSQLDefaultPrivilegesCreate,
+		Read:   resourcePostgreSQLDefaultPrivilegesRead,
+		Update: resourcePostgreSQLDefaultPrivilegesUpdate,
+		Delete: resourcePostgreSQLDefaultPrivilegesDelete,
+
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The database to grant default privileges for this role",
+			},
+			"owner": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Target role for which to alter default privileges",
+			},
+			"schema": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The database schema to set default privileges for this role",
+			},
+			"role": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the role to which grant default privileges on",
+			},
+			"object_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The PostgreSQL object type to set the default privileges on (one of: " + strings.Join(validDefaultPrivilegesObjectTypes, ", ") + ")",
+				ValidateFunc: validation.StringInSlice(validDefaultPrivilegesObjectTypes, true),
+			},
+			"privileges": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The list of privileges that should be set as default for the specified owner and object_type",
+			},
+			"with_grant_option": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+				Description: "Permit the grantee to grant the privileges to other roles",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLDefaultPrivilegesCreate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*Client)
+	c.catalogLock.Lock()
+	defer c.catalogLock.Unlock()
+
+	conn, err := c.Connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	privileges := []string{}
+	for _, p := range d.Get("privileges").(*schema.Set).List() {
+		privileges = append(privileges, p.(string))
+	}
+
+	query := createDefaultPrivilegesGrantQuery(d, privileges)
+	if _, err := conn.Exec(query); err != nil {
+		return fmt.Errorf("could not execute default privileges grant query: %w", err)
+	}
+
+	d.SetId(generateDefaultPrivilegesID(d))
+
+	return resourcePostgreSQLDefaultPrivilegesRead(d, meta)
+}
+
+func resourcePostgreSQLDefaultPrivilegesUpdate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*Client)
+	c.catalogLock.Lock()
+	defer c.catalogLock.Unlock()
+
+	conn, err := c.Connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Exec(createDefaultPrivilegesRevokeQuery(d)); err != nil {
+		return fmt.Errorf("could not execute default privileges revoke query: %w", err)
+	}
+
+	privileges := []string{}
+	for _, p := range d.Get("privileges").(*schema.Set).List() {
+		privileges = append(privileges, p.(string))
+	}
+
+	query := createDefaultPrivilegesGrantQuery(d, privileges)
+	if _, err := conn.Exec(query); err != nil {
+		return fmt.Errorf("could not execute default privileges grant query: %w", err)
+	}
+
+	return resourcePostgreSQLDefaultPrivilegesRead(d, meta)
+}
+
+func resourcePostgreSQLDefaultPrivilegesRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*Client)
+	c.catalogLock.RLock()
+	defer c.catalogLock.RUnlock()
+
+	conn, err := c.Connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	objectType := strings.ToLower(d.Get("object_type").(string))
+	objType, ok := defaultPrivilegesObjectTypes[objectType]
+	if !ok {
+		return fmt.Errorf("unknown object_type %q", objectType)
+	}
+
+	privileges, withGrantOption, err := readDefaultACL(
+		conn,
+		d.Get("owner").(string),
+		d.Get("schema").(string),
+		objType,
+		d.Get("role").(string),
+	)
+	if err != nil {
+		return fmt.Errorf("could not read default privileges for role %s: %w", d.Get("role").(string), err)
+	}
+
+	if len(privileges) == 0 {
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("privileges", privileges); err != nil {
+		return err
+	}
+	return d.Set("with_grant_option", withGrantOption)
+}
+
+func resourcePostgreSQLDefaultPrivilegesDelete(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*Client)
+	c.catalogLock.Lock()
+	defer c.catalogLock.Unlock()
+
+	conn, err := c.Connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	query := createDefaultPrivilegesRevokeQuery(d)
+	if _, err := conn.Exec(query); err != nil {
+		return fmt.Errorf("could not execute default privileges revoke query: %w", err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func createDefaultPrivilegesGrantQuery(d *schema.ResourceData, privileges []string) string {
+	query := fmt.Sprintf(
+		"ALTER DEFAULT PRIVILEGES FOR ROLE %s IN SCHEMA %s GRANT %s ON %s TO %s",
+		pq.QuoteIdentifier(d.Get("owner").(string)),
+		pq.QuoteIdentifier(d.Get("schema").(string)),
+		strings.Join(privileges, ","),
+		defaultPrivilegesObjectKeyword(d.Get("object_type").(string)),
+		pq.QuoteIdentifier(d.Get("role").(string)),
+	)
+
+	if d.Get("with_grant_option").(bool) {
+		query += " WITH GRANT OPTION"
+	}
+
+	return query
+}
+
+func createDefaultPrivilegesRevokeQuery(d *schema.ResourceData) string {
+	return fmt.Sprintf(
+		"ALTER DEFAULT PRIVILEGES FOR ROLE %s IN SCHEMA %s REVOKE ALL PRIVILEGES ON %s FROM %s",
+		pq.QuoteIdentifier(d.Get("owner").(string)),
+		pq.QuoteIdentifier(d.Get("schema").(string)),
+		defaultPrivilegesObjectKeyword(d.Get("object_type").(string)),
+		pq.QuoteIdentifier(d.Get("role").(string)),
+	)
+}
+
+// defaultPrivilegesObjectKeyword returns the plural keyword ALTER DEFAULT
+// PRIVILEGES expects after ON, e.g. "TABLES" for object_type "table".
+func defaultPrivilegesObjectKeyword(objectType string) string {
+	switch strings.ToLower(objectType) {
+	case "table":
+		return "TABLES"
+	case "sequence":
+		return "SEQUENCES"
+	case "function":
+		return "FUNCTIONS"
+	case "type":
+		return "TYPES"
+	default:
+		return strings.ToUpper(objectType) + "S"
+	}
+}
+
+// readDefaultACL decodes pg_default_acl.defaclacl for the given owner/schema/
+// object type and returns the privileges granted to role, along with whether
+// they were granted WITH GRANT OPTION.
+func readDefaultACL(conn *sql.DB, owner, schemaName, objType, role string) ([]string, bool, error) {
+	query := `
+SELECT defaclacl
+FROM pg_default_acl
+JOIN pg_roles ON pg_roles.oid = pg_default_acl.defaclrole
+JOIN pg_namespace ON pg_namespace.oid = pg_default_acl.defaclnamespace
+WHERE pg_roles.rolname = $1
+  AND pg_namespace.nspname = $2
+  AND defaclobjtype = $3
+`
+	var acl []string
+	err := conn.QueryRow(query, owner, schemaName, objType).Scan(pq.Array(&acl))
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, item := range acl {
+		grantee, privileges, withGrantOption := decodeACLItem(item)
+		if grantee != role {
+			continue
+		}
+		return privileges, withGrantOption, nil
+	}
+
+	return nil, false, nil
+}
+
+// aclPrivilegeCodes maps the single-letter privilege codes used inside an
+// aclitem (e.g. "bar=arwdDxt/foo") to their SQL privilege names.
+var aclPrivilegeCodes = map[byte]string{
+	'r': "SELECT",
+	'a': "INSERT",
+	'w': "UPDATE",
+	'd': "DELETE",
+	'D': "TRUNCATE",
+	'x': "REFERENCES",
+	't': "TRIGGER",
+	'X': "EXECUTE",
+	'U': "USAGE",
+	'C': "CREATE",
+	'c': "CONNECT",
+	'T': "TEMPORARY",
+}
+
+// decodeACLItem parses a single aclitem, e.g. "bar=arwdDxt*/foo", into the
+// grantee name, the list of privileges it was granted, and whether any of
+// them carry the grant option (marked with a trailing "*" in the code).
+func decodeACLItem(item string) (grantee string, privileges []string, withGrantOption bool) {
+	parts := strings.SplitN(item, "=", 2)
+	if len(parts) != 2 {
+		return "", nil, false
+	}
+	grantee = parts[0]
+
+	codes := strings.SplitN(parts[1], "/", 2)[0]
+	for i := 0; i < len(codes); i++ {
+		code := codes[i]
+		if code == '*' {
+			withGrantOption = true
+			continue
+		}
+		if name, ok := aclPrivilegeCodes[code]; ok {
+			privileges = append(privileges, name)
+		}
+	}
+
+	return grantee, privileges, withGrantOption
+}
+
+func generateDefaultPrivilegesID(d *schema.ResourceData) string {
+	return strings.Join([]string{
+		d.Get("database").(string),
+		d.Get("owner").(string),
+		d.Get("schema").(string),
+		d.Get("role").(string),
+		d.Get("object_type").(string),
+	}, "_")
+}
@@ -0,0 +1,1058 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/lib/pq"
+)
+
+// objectsSupportedTypes lists the object_type values for which createGrantQuery
+// can target a specific set of objects instead of every object in the schema.
+var objectsSupportedTypes = []string{"table", "sequence", "function", "procedure"}
+
+// globalObjectTypes lists the object_type values whose objects aren't scoped
+// to a schema (foreign data wrappers, foreign servers, languages are
+// cluster-wide), so objects is the only way to name what to grant on.
+var globalObjectTypes = []string{"foreign_data_wrapper", "foreign_server", "language"}
+
+var validObjectTypes = []string{
+	"database",
+	"schema",
+	"table",
+	"sequence",
+	"function",
+	"procedure",
+	"foreign_data_wrapper",
+	"foreign_server",
+	"language",
+}
+
+// grantReplacements maps an object_type to the full set of privileges that
+// PostgreSQL substitutes for "ALL"/"ALL PRIVILEGES" when it reports grants
+// back through information_schema/pg_catalog. Keeping this expansion in one
+// place lets Read compare what PostgreSQL actually stores against whichever
+// shorthand the user wrote in HCL, and lets us collapse the expansion back
+// down so a plan stays stable no matter which form was used.
+var grantReplacements = map[string][]string{
+	"database":             {"CREATE", "TEMPORARY", "CONNECT"},
+	"table":                {"SELECT", "INSERT", "UPDATE", "DELETE", "TRUNCATE", "REFERENCES", "TRIGGER"},
+	"sequence":             {"USAGE", "SELECT", "UPDATE"},
+	"schema":               {"CREATE", "USAGE"},
+	"function":             {"EXECUTE"},
+	"procedure":            {"EXECUTE"},
+	"foreign_data_wrapper": {"USAGE"},
+	"foreign_server":       {"USAGE"},
+	"language":             {"USAGE"},
+}
+
+func resourcePostgreSQLGrant() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePostgreSQLGrantCreate,
+		Read:   resourcePostgreSQLGrantRead,
+		Update: resourcePostgreSQLGrantUpdate,
+		Delete: resourcePostgreSQLGrantDelete,
+
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The database to grant privileges on for this role",
+			},
+			"role": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				Description:   "The name of the role to grant privileges on. Exactly one of \"role\" or \"roles\" must be set",
+				ConflictsWith: []string{"roles"},
+			},
+			"roles": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				ForceNew:      true,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				Description:   "The names of the roles to grant privileges on, as an alternative to a single \"role\". The literal \"public\" (case-insensitive) is translated to the PUBLIC pseudo-role",
+				ConflictsWith: []string{"role"},
+			},
+			"schema": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The database schema to grant privileges on for this role",
+			},
+			"object_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The PostgreSQL object type to grant the privileges on (one of: " + strings.Join(validObjectTypes, ", ") + ")",
+				ValidateFunc: validation.StringInSlice(validObjectTypes, true),
+			},
+			"objects": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The specific objects to grant privileges on for this role (e.g. a list of tables). Required for object_type foreign_data_wrapper, foreign_server and language; otherwise only accepted for table, sequence, function and procedure, where leaving it unset grants on all objects of the given object_type in the schema",
+			},
+			"privileges": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The list of privileges to grant",
+			},
+			"columns": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Restricts each privilege that has an entry here to the listed columns; privileges in \"privileges\" without an entry are granted on the whole table. Only valid for object_type \"table\" combined with a single entry in \"objects\"",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"privilege": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "The privilege this column list applies to, one of the entries in \"privileges\"",
+							ValidateFunc: validation.StringInSlice([]string{"SELECT", "INSERT", "UPDATE", "REFERENCES"}, true),
+						},
+						"columns": {
+							Type:        schema.TypeSet,
+							Required:    true,
+							MinItems:    1,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The columns privilege is granted on",
+						},
+					},
+				},
+			},
+			"with_grant_option": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+				Description: "Permit the grantee to grant the privileges to other roles",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLGrantCreate(d *schema.ResourceData, meta interface{}) error {
+	if err := resourcePostgreSQLGrantApply(d, meta); err != nil {
+		return err
+	}
+
+	d.SetId(generateGrantID(d))
+
+	return resourcePostgreSQLGrantRead(d, meta)
+}
+
+func resourcePostgreSQLGrantUpdate(d *schema.ResourceData, meta interface{}) error {
+	if err := resourcePostgreSQLGrantApply(d, meta); err != nil {
+		return err
+	}
+
+	return resourcePostgreSQLGrantRead(d, meta)
+}
+
+// resourcePostgreSQLGrantApply revokes whatever this resource previously
+// granted and grants the privileges currently in config, so that shrinking
+// "privileges" on Update actually takes the removed privileges away instead
+// of just adding to whatever is already granted.
+func resourcePostgreSQLGrantApply(d *schema.ResourceData, meta interface{}) error {
+	if err := validateRoleAttributes(d); err != nil {
+		return err
+	}
+	if err := validateObjectsAttribute(d); err != nil {
+		return err
+	}
+	if err := validateColumnsAttribute(d); err != nil {
+		return err
+	}
+
+	c := meta.(*Client)
+	c.catalogLock.Lock()
+	defer c.catalogLock.Unlock()
+
+	conn, err := c.Connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if strings.ToLower(d.Get("object_type").(string)) == "procedure" {
+		supported, err := featureSupported(conn, featureProcedure)
+		if err != nil {
+			return err
+		}
+		if !supported {
+			return fmt.Errorf("object_type procedure is only supported on PostgreSQL >= 11")
+		}
+	}
+
+	if _, err := conn.Exec(createRevokeQuery(d)); err != nil {
+		return fmt.Errorf("could not execute revoke query: %w", err)
+	}
+
+	privileges := expandPrivileges(d)
+
+	query := createGrantQuery(d, privileges)
+	if _, err := conn.Exec(query); err != nil {
+		return fmt.Errorf("could not execute grant query: %w", err)
+	}
+
+	return nil
+}
+
+func resourcePostgreSQLGrantRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*Client)
+	c.catalogLock.RLock()
+	defer c.catalogLock.RUnlock()
+
+	conn, err := c.Connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	objectType := strings.ToUpper(d.Get("object_type").(string))
+
+	objects := expandObjects(d)
+
+	if objectType == "TABLE" && len(expandColumns(d)) > 0 {
+		return resourcePostgreSQLGrantReadColumns(d, conn, objects)
+	}
+
+	var observed []string
+	switch objectType {
+	case "DATABASE":
+		observed, err = privilegesForAllRoles(d, func(role string) ([]string, error) {
+			return readACLPrivileges(conn, "SELECT datacl FROM pg_database WHERE datname = $1", d.Get("database").(string), role)
+		})
+	case "TABLE":
+		observed, err = privilegesForAllRoles(d, func(role string) ([]string, error) {
+			return readTablePrivileges(conn, d.Get("schema").(string), granteeQueryValue(role), objects)
+		})
+	case "SEQUENCE":
+		observed, err = privilegesForAllRoles(d, func(role string) ([]string, error) {
+			return readSequencePrivileges(conn, d.Get("schema").(string), granteeQueryValue(role), objects)
+		})
+	case "FUNCTION", "PROCEDURE":
+		observed, err = privilegesForAllRoles(d, func(role string) ([]string, error) {
+			return readRoutinePrivileges(conn, objectType, d.Get("schema").(string), granteeQueryValue(role), objects)
+		})
+	case "SCHEMA":
+		observed, err = privilegesForAllRoles(d, func(role string) ([]string, error) {
+			return readACLPrivileges(conn, "SELECT nspacl FROM pg_namespace WHERE nspname = $1", d.Get("schema").(string), role)
+		})
+	case "FOREIGN_DATA_WRAPPER":
+		observed, err = privilegesForAllRoles(d, func(role string) ([]string, error) {
+			return readACLPrivilegesForObjects(conn, "SELECT fdwacl FROM pg_foreign_data_wrapper WHERE fdwname = $1", objects, role)
+		})
+	case "FOREIGN_SERVER":
+		observed, err = privilegesForAllRoles(d, func(role string) ([]string, error) {
+			return readACLPrivilegesForObjects(conn, "SELECT srvacl FROM pg_foreign_server WHERE srvname = $1", objects, role)
+		})
+	case "LANGUAGE":
+		observed, err = privilegesForAllRoles(d, func(role string) ([]string, error) {
+			return readACLPrivilegesForObjects(conn, "SELECT lanacl FROM pg_language WHERE lanname = $1", objects, role)
+		})
+	default:
+		// Other object types don't have a Create/Revoke implementation yet,
+		// so there is nothing in the database to reconcile against.
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not read privileges for roles %s: %w", strings.Join(expandRoles(d), ", "), err)
+	}
+
+	if len(observed) == 0 {
+		d.SetId("")
+		return nil
+	}
+
+	// Report a full expansion in whichever form config uses, so that neither
+	// "ALL"/"ALL PRIVILEGES" nor the spelled-out list shows as drift.
+	if configured := d.Get("privileges").(*schema.Set).List(); len(configured) == 1 {
+		switch shorthand := configured[0].(string); strings.ToUpper(shorthand) {
+		case "ALL", "ALL PRIVILEGES":
+			collapsed := collapseGrantPrivileges(d.Get("object_type").(string), observed)
+			if len(collapsed) == 1 && collapsed[0] == "ALL PRIVILEGES" {
+				observed = []string{shorthand}
+			}
+		}
+	}
+
+	return d.Set("privileges", observed)
+}
+
+// resourcePostgreSQLGrantReadColumns is the Read codepath for a grant scoped
+// to specific columns: it rebuilds the privilege -> columns map from
+// information_schema.column_privileges instead of comparing flat privilege
+// lists.
+func resourcePostgreSQLGrantReadColumns(d *schema.ResourceData, conn *sql.DB, objects []string) error {
+	if len(objects) != 1 {
+		d.SetId("")
+		return nil
+	}
+
+	roles := expandRoles(d)
+	perRole := make([]map[string][]string, 0, len(roles))
+	for _, role := range roles {
+		m, err := readColumnPrivileges(conn, d.Get("schema").(string), objects[0], granteeQueryValue(role))
+		if err != nil {
+			return fmt.Errorf("could not read column privileges for role %s: %w", role, err)
+		}
+		perRole = append(perRole, m)
+	}
+	columnsByPrivilege := intersectColumnPrivileges(perRole)
+
+	if len(columnsByPrivilege) == 0 {
+		d.SetId("")
+		return nil
+	}
+
+	privileges := make([]string, 0, len(columnsByPrivilege))
+	columns := make([]interface{}, 0, len(columnsByPrivilege))
+	for privilege, cols := range columnsByPrivilege {
+		privileges = append(privileges, privilege)
+		columns = append(columns, map[string]interface{}{
+			"privilege": privilege,
+			"columns":   cols,
+		})
+	}
+
+	if err := d.Set("privileges", privileges); err != nil {
+		return err
+	}
+	return d.Set("columns", columns)
+}
+
+func resourcePostgreSQLGrantDelete(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*Client)
+	c.catalogLock.Lock()
+	defer c.catalogLock.Unlock()
+
+	conn, err := c.Connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	query := createRevokeQuery(d)
+	if _, err := conn.Exec(query); err != nil {
+		return fmt.Errorf("could not execute revoke query: %w", err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func createGrantQuery(d *schema.ResourceData, privileges []string) string {
+	var query string
+	objectType := strings.ToUpper(d.Get("object_type").(string))
+
+	switch objectType {
+	case "DATABASE":
+		query = fmt.Sprintf(
+			"GRANT %s ON DATABASE %s TO %s",
+			strings.Join(privileges, ","),
+			pq.QuoteIdentifier(d.Get("database").(string)),
+			granteeList(d),
+		)
+	case "TABLE":
+		if columns := expandColumns(d); len(columns) > 0 {
+			query = fmt.Sprintf(
+				"GRANT %s ON TABLE %s TO %s",
+				columnScopedPrivilegeList(privileges, columns),
+				qualifiedObjects(d.Get("schema").(string), expandObjects(d)),
+				granteeList(d),
+			)
+			break
+		}
+		if objects := expandObjects(d); len(objects) > 0 {
+			query = fmt.Sprintf(
+				"GRANT %s ON TABLE %s TO %s",
+				strings.Join(privileges, ","),
+				qualifiedObjects(d.Get("schema").(string), objects),
+				granteeList(d),
+			)
+			break
+		}
+		query = fmt.Sprintf(
+			"GRANT %s ON ALL TABLES IN SCHEMA %s TO %s",
+			strings.Join(privileges, ","),
+			pq.QuoteIdentifier(d.Get("schema").(string)),
+			granteeList(d),
+		)
+	case "SEQUENCE":
+		if objects := expandObjects(d); len(objects) > 0 {
+			query = fmt.Sprintf(
+				"GRANT %s ON SEQUENCE %s TO %s",
+				strings.Join(privileges, ","),
+				qualifiedObjects(d.Get("schema").(string), objects),
+				granteeList(d),
+			)
+			break
+		}
+		query = fmt.Sprintf(
+			"GRANT %s ON ALL SEQUENCES IN SCHEMA %s TO %s",
+			strings.Join(privileges, ","),
+			pq.QuoteIdentifier(d.Get("schema").(string)),
+			granteeList(d),
+		)
+	case "FUNCTION", "PROCEDURE":
+		kind := objectType + "S"
+		if objects := expandObjects(d); len(objects) > 0 {
+			query = fmt.Sprintf(
+				"GRANT %s ON %s %s TO %s",
+				strings.Join(privileges, ","),
+				objectType,
+				qualifiedObjects(d.Get("schema").(string), objects),
+				granteeList(d),
+			)
+			break
+		}
+		query = fmt.Sprintf(
+			"GRANT %s ON ALL %s IN SCHEMA %s TO %s",
+			strings.Join(privileges, ","),
+			kind,
+			pq.QuoteIdentifier(d.Get("schema").(string)),
+			granteeList(d),
+		)
+	case "SCHEMA":
+		query = fmt.Sprintf(
+			"GRANT %s ON SCHEMA %s TO %s",
+			strings.Join(privileges, ","),
+			pq.QuoteIdentifier(d.Get("schema").(string)),
+			granteeList(d),
+		)
+	case "FOREIGN_DATA_WRAPPER":
+		query = fmt.Sprintf(
+			"GRANT %s ON FOREIGN DATA WRAPPER %s TO %s",
+			strings.Join(privileges, ","),
+			quotedIdentifierList(expandObjects(d)),
+			granteeList(d),
+		)
+	case "FOREIGN_SERVER":
+		query = fmt.Sprintf(
+			"GRANT %s ON FOREIGN SERVER %s TO %s",
+			strings.Join(privileges, ","),
+			quotedIdentifierList(expandObjects(d)),
+			granteeList(d),
+		)
+	case "LANGUAGE":
+		query = fmt.Sprintf(
+			"GRANT %s ON LANGUAGE %s TO %s",
+			strings.Join(privileges, ","),
+			quotedIdentifierList(expandObjects(d)),
+			granteeList(d),
+		)
+	}
+
+	if d.Get("with_grant_option").(bool) {
+		query = query + " WITH GRANT OPTION"
+	}
+
+	return query
+}
+
+func createRevokeQuery(d *schema.ResourceData) string {
+	var query string
+	objectType := strings.ToUpper(d.Get("object_type").(string))
+
+	switch objectType {
+	case "DATABASE":
+		query = fmt.Sprintf(
+			"REVOKE ALL PRIVILEGES ON DATABASE %s FROM %s",
+			pq.QuoteIdentifier(d.Get("database").(string)),
+			granteeList(d),
+		)
+	case "TABLE":
+		if columns := expandColumns(d); len(columns) > 0 {
+			// columns is ForceNew, so its keys are the full set of
+			// privileges this resource could ever have granted on these
+			// columns, even if "privileges" has since shrunk on a plain
+			// Update. Revoke all of them, not just what's left in
+			// "privileges", or the dropped ones are never revoked.
+			privileges := make([]string, 0, len(columns))
+			for privilege := range columns {
+				privileges = append(privileges, privilege)
+			}
+			// Previously configured privileges with no columns entry were
+			// granted on the whole table (see columnScopedPrivilegeList),
+			// so revoke those as well.
+			old, _ := d.GetChange("privileges")
+			oldPrivileges := []string{}
+			for _, p := range old.(*schema.Set).List() {
+				oldPrivileges = append(oldPrivileges, p.(string))
+			}
+			for _, p := range expandGrantPrivileges("table", oldPrivileges) {
+				if _, ok := columns[strings.ToUpper(p)]; !ok {
+					privileges = append(privileges, p)
+				}
+			}
+			sort.Strings(privileges)
+			query = fmt.Sprintf(
+				"REVOKE %s ON TABLE %s FROM %s",
+				columnScopedPrivilegeList(privileges, columns),
+				qualifiedObjects(d.Get("schema").(string), expandObjects(d)),
+				granteeList(d),
+			)
+			break
+		}
+		if objects := expandObjects(d); len(objects) > 0 {
+			query = fmt.Sprintf(
+				"REVOKE ALL PRIVILEGES ON TABLE %s FROM %s",
+				qualifiedObjects(d.Get("schema").(string), objects),
+				granteeList(d),
+			)
+			break
+		}
+		query = fmt.Sprintf(
+			"REVOKE ALL PRIVILEGES ON ALL TABLES IN SCHEMA %s FROM %s",
+			pq.QuoteIdentifier(d.Get("schema").(string)),
+			granteeList(d),
+		)
+	case "SEQUENCE":
+		if objects := expandObjects(d); len(objects) > 0 {
+			query = fmt.Sprintf(
+				"REVOKE ALL PRIVILEGES ON SEQUENCE %s FROM %s",
+				qualifiedObjects(d.Get("schema").(string), objects),
+				granteeList(d),
+			)
+			break
+		}
+		query = fmt.Sprintf(
+			"REVOKE ALL PRIVILEGES ON ALL SEQUENCES IN SCHEMA %s FROM %s",
+			pq.QuoteIdentifier(d.Get("schema").(string)),
+			granteeList(d),
+		)
+	case "FUNCTION", "PROCEDURE":
+		kind := objectType + "S"
+		if objects := expandObjects(d); len(objects) > 0 {
+			query = fmt.Sprintf(
+				"REVOKE ALL PRIVILEGES ON %s %s FROM %s",
+				objectType,
+				qualifiedObjects(d.Get("schema").(string), objects),
+				granteeList(d),
+			)
+			break
+		}
+		query = fmt.Sprintf(
+			"REVOKE ALL PRIVILEGES ON ALL %s IN SCHEMA %s FROM %s",
+			kind,
+			pq.QuoteIdentifier(d.Get("schema").(string)),
+			granteeList(d),
+		)
+	case "SCHEMA":
+		query = fmt.Sprintf(
+			"REVOKE ALL PRIVILEGES ON SCHEMA %s FROM %s",
+			pq.QuoteIdentifier(d.Get("schema").(string)),
+			granteeList(d),
+		)
+	case "FOREIGN_DATA_WRAPPER":
+		query = fmt.Sprintf(
+			"REVOKE ALL PRIVILEGES ON FOREIGN DATA WRAPPER %s FROM %s",
+			quotedIdentifierList(expandObjects(d)),
+			granteeList(d),
+		)
+	case "FOREIGN_SERVER":
+		query = fmt.Sprintf(
+			"REVOKE ALL PRIVILEGES ON FOREIGN SERVER %s FROM %s",
+			quotedIdentifierList(expandObjects(d)),
+			granteeList(d),
+		)
+	case "LANGUAGE":
+		query = fmt.Sprintf(
+			"REVOKE ALL PRIVILEGES ON LANGUAGE %s FROM %s",
+			quotedIdentifierList(expandObjects(d)),
+			granteeList(d),
+		)
+	}
+
+	return query
+}
+
+// validateRoleAttributes enforces that exactly one of "role"/"roles" is set;
+// ConflictsWith only rules out having both.
+func validateRoleAttributes(d *schema.ResourceData) error {
+	_, hasRole := d.GetOk("role")
+	_, hasRoles := d.GetOk("roles")
+
+	if !hasRole && !hasRoles {
+		return fmt.Errorf("one of role or roles must be set")
+	}
+
+	return nil
+}
+
+// expandRoles returns the grantees to use in the GRANT/REVOKE statement,
+// from whichever of "role"/"roles" is set.
+func expandRoles(d *schema.ResourceData) []string {
+	if roles, ok := d.GetOk("roles"); ok {
+		grantees := []string{}
+		for _, r := range roles.(*schema.Set).List() {
+			grantees = append(grantees, r.(string))
+		}
+		return grantees
+	}
+
+	return []string{d.Get("role").(string)}
+}
+
+// primaryRole returns the first grantee, for codepaths such as ID generation
+// that just need a single stable representative and don't reconcile state.
+func primaryRole(d *schema.ResourceData) string {
+	return expandRoles(d)[0]
+}
+
+// privilegesForAllRoles calls readOne once per grantee named by "role"/
+// "roles" and returns the privileges common to every one of them, so that a
+// privilege dropped from any single role — not just the first — surfaces as
+// drift instead of being silently missed.
+func privilegesForAllRoles(d *schema.ResourceData, readOne func(role string) ([]string, error)) ([]string, error) {
+	roles := expandRoles(d)
+	sets := make([][]string, 0, len(roles))
+	for _, role := range roles {
+		privileges, err := readOne(role)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, privileges)
+	}
+	return intersectPrivileges(sets), nil
+}
+
+// intersectPrivileges returns the privileges present in every set in sets,
+// so a privilege missing from any one of them drops out of the result.
+func intersectPrivileges(sets [][]string) []string {
+	if len(sets) == 0 {
+		return nil
+	}
+
+	counts := map[string]int{}
+	for _, set := range sets {
+		seen := map[string]bool{}
+		for _, privilege := range set {
+			key := strings.ToUpper(privilege)
+			if !seen[key] {
+				counts[key]++
+				seen[key] = true
+			}
+		}
+	}
+
+	var result []string
+	for privilege, count := range counts {
+		if count == len(sets) {
+			result = append(result, privilege)
+		}
+	}
+	return result
+}
+
+// intersectColumnPrivileges returns, for each privilege, only the columns
+// that every entry in perRole agrees are granted that privilege.
+func intersectColumnPrivileges(perRole []map[string][]string) map[string][]string {
+	if len(perRole) == 0 {
+		return nil
+	}
+
+	result := map[string][]string{}
+	for privilege, columns := range perRole[0] {
+		for _, column := range columns {
+			inAll := true
+			for _, other := range perRole[1:] {
+				if !containsString(other[privilege], column) {
+					inAll = false
+					break
+				}
+			}
+			if inAll {
+				result[privilege] = append(result[privilege], column)
+			}
+		}
+	}
+	return result
+}
+
+func containsString(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+	return false
+}
+
+// granteeList renders the grantee(s) for a GRANT/REVOKE statement, quoting
+// role names but leaving the literal "public" (case-insensitive) as the
+// unquoted PUBLIC pseudo-role.
+func granteeList(d *schema.ResourceData) string {
+	roles := expandRoles(d)
+	quoted := make([]string, len(roles))
+	for i, role := range roles {
+		if strings.EqualFold(role, "public") {
+			quoted[i] = "PUBLIC"
+			continue
+		}
+		quoted[i] = pq.QuoteIdentifier(role)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// validateObjectsAttribute enforces the constraints that the schema alone
+// can't express: objects only makes sense for object types that grant on
+// individual objects, and it needs a schema to qualify those objects with.
+func validateObjectsAttribute(d *schema.ResourceData) error {
+	objectType := strings.ToLower(d.Get("object_type").(string))
+	objects := expandObjects(d)
+
+	for _, t := range globalObjectTypes {
+		if t == objectType {
+			if len(objects) == 0 {
+				return fmt.Errorf("objects is required for object_type %q", objectType)
+			}
+			return nil
+		}
+	}
+
+	if len(objects) == 0 {
+		return nil
+	}
+
+	supported := false
+	for _, t := range objectsSupportedTypes {
+		if t == objectType {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return fmt.Errorf("objects is not supported for object_type %q (must be one of: %s)", objectType, strings.Join(objectsSupportedTypes, ", "))
+	}
+
+	if d.Get("schema").(string) == "" {
+		return fmt.Errorf("schema must be set when objects is specified")
+	}
+
+	return nil
+}
+
+// validateColumnsAttribute enforces that columns is only used where a GRANT
+// can actually be scoped to a column list: a single table.
+func validateColumnsAttribute(d *schema.ResourceData) error {
+	columns := expandColumns(d)
+	if len(columns) == 0 {
+		return nil
+	}
+
+	if strings.ToLower(d.Get("object_type").(string)) != "table" {
+		return fmt.Errorf("columns is only supported for object_type \"table\"")
+	}
+
+	if len(expandObjects(d)) != 1 {
+		return fmt.Errorf("columns requires objects to contain exactly one table")
+	}
+
+	return nil
+}
+
+// expandColumns returns the privilege -> columns map described by the
+// columns attribute.
+func expandColumns(d *schema.ResourceData) map[string][]string {
+	columns := map[string][]string{}
+	for _, raw := range d.Get("columns").(*schema.Set).List() {
+		entry := raw.(map[string]interface{})
+		privilege := strings.ToUpper(entry["privilege"].(string))
+		for _, c := range entry["columns"].(*schema.Set).List() {
+			columns[privilege] = append(columns[privilege], c.(string))
+		}
+	}
+	return columns
+}
+
+// columnScopedPrivilegeList renders one "PRIVILEGE (col1, col2)" clause per
+// privilege, falling back to an unscoped clause for privileges that have no
+// entry in columns.
+func columnScopedPrivilegeList(privileges []string, columns map[string][]string) string {
+	clauses := make([]string, len(privileges))
+	for i, privilege := range privileges {
+		cols, ok := columns[strings.ToUpper(privilege)]
+		if !ok {
+			clauses[i] = privilege
+			continue
+		}
+
+		sorted := append([]string{}, cols...)
+		sort.Strings(sorted)
+
+		quoted := make([]string, len(sorted))
+		for j, c := range sorted {
+			quoted[j] = pq.QuoteIdentifier(c)
+		}
+		clauses[i] = fmt.Sprintf("%s (%s)", privilege, strings.Join(quoted, ", "))
+	}
+	return strings.Join(clauses, ", ")
+}
+
+func expandObjects(d *schema.ResourceData) []string {
+	objects := []string{}
+	for _, o := range d.Get("objects").([]interface{}) {
+		objects = append(objects, o.(string))
+	}
+	return objects
+}
+
+func qualifiedObjects(schemaName string, objects []string) string {
+	quoted := make([]string, len(objects))
+	for i, object := range objects {
+		quoted[i] = fmt.Sprintf("%s.%s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(object))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// quotedIdentifierList quotes each of objects without schema-qualifying them,
+// for cluster-wide objects such as foreign servers, foreign data wrappers,
+// and languages.
+func quotedIdentifierList(objects []string) string {
+	quoted := make([]string, len(objects))
+	for i, object := range objects {
+		quoted[i] = pq.QuoteIdentifier(object)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func expandPrivileges(d *schema.ResourceData) []string {
+	privileges := []string{}
+	for _, p := range d.Get("privileges").(*schema.Set).List() {
+		privileges = append(privileges, p.(string))
+	}
+	return expandGrantPrivileges(d.Get("object_type").(string), privileges)
+}
+
+// expandGrantPrivileges replaces an "ALL"/"ALL PRIVILEGES" shorthand with the
+// full set PostgreSQL would store for objectType, so that a Create issued
+// with the shorthand and one issued with the expanded list are equivalent.
+func expandGrantPrivileges(objectType string, privileges []string) []string {
+	if len(privileges) != 1 {
+		return privileges
+	}
+
+	switch strings.ToUpper(privileges[0]) {
+	case "ALL", "ALL PRIVILEGES":
+		if expanded, ok := grantReplacements[strings.ToLower(objectType)]; ok {
+			return expanded
+		}
+	}
+
+	return privileges
+}
+
+// collapseGrantPrivileges is the inverse of expandGrantPrivileges: when the
+// privileges PostgreSQL reports for objectType are exactly the full
+// expansion, it collapses them back to "ALL PRIVILEGES" so that plans stay
+// stable regardless of which form the user wrote in HCL.
+func collapseGrantPrivileges(objectType string, privileges []string) []string {
+	expansion, ok := grantReplacements[strings.ToLower(objectType)]
+	if !ok || len(privileges) != len(expansion) {
+		return privileges
+	}
+
+	got := append([]string{}, privileges...)
+	want := append([]string{}, expansion...)
+	sort.Strings(got)
+	sort.Strings(want)
+
+	for i := range got {
+		if !strings.EqualFold(got[i], want[i]) {
+			return privileges
+		}
+	}
+
+	return []string{"ALL PRIVILEGES"}
+}
+
+func readTablePrivileges(conn *sql.DB, schemaName, role string, objects []string) ([]string, error) {
+	query := `
+SELECT DISTINCT privilege_type
+FROM information_schema.table_privileges
+WHERE table_schema = $1
+  AND grantee = $2
+`
+	args := []interface{}{schemaName, role}
+	if len(objects) > 0 {
+		placeholder, args2 := inClause(args, objects)
+		query += fmt.Sprintf("  AND table_name IN (%s)\n", placeholder)
+		args = args2
+	}
+	return queryGrantedPrivileges(conn, query, args...)
+}
+
+func readSequencePrivileges(conn *sql.DB, schemaName, role string, objects []string) ([]string, error) {
+	query := `
+SELECT DISTINCT privilege_type
+FROM information_schema.role_usage_grants
+WHERE object_schema = $1
+  AND object_type = 'SEQUENCE'
+  AND grantee = $2
+`
+	args := []interface{}{schemaName, role}
+	if len(objects) > 0 {
+		placeholder, args2 := inClause(args, objects)
+		query += fmt.Sprintf("  AND object_name IN (%s)\n", placeholder)
+		args = args2
+	}
+	return queryGrantedPrivileges(conn, query, args...)
+}
+
+// readColumnPrivileges returns the privilege -> columns map granted to role
+// on table, derived from information_schema.column_privileges.
+func readColumnPrivileges(conn *sql.DB, schemaName, table, role string) (map[string][]string, error) {
+	rows, err := conn.Query(`
+SELECT privilege_type, column_name
+FROM information_schema.column_privileges
+WHERE table_schema = $1
+  AND table_name = $2
+  AND grantee = $3
+ORDER BY privilege_type, column_name
+`, schemaName, table, role)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columnsByPrivilege := map[string][]string{}
+	for rows.Next() {
+		var privilege, column string
+		if err := rows.Scan(&privilege, &column); err != nil {
+			return nil, err
+		}
+		columnsByPrivilege[privilege] = append(columnsByPrivilege[privilege], column)
+	}
+
+	return columnsByPrivilege, rows.Err()
+}
+
+func readRoutinePrivileges(conn *sql.DB, objectType, schemaName, role string, objects []string) ([]string, error) {
+	query := `
+SELECT DISTINCT privilege_type
+FROM information_schema.routine_privileges
+WHERE specific_schema = $1
+  AND grantee = $2
+  AND routine_type = $3
+`
+	args := []interface{}{schemaName, role, objectType}
+	if len(objects) > 0 {
+		placeholder, args2 := inClause(args, objects)
+		query += fmt.Sprintf("  AND routine_name IN (%s)\n", placeholder)
+		args = args2
+	}
+	return queryGrantedPrivileges(conn, query, args...)
+}
+
+// readACLPrivileges decodes the aclitem[] column returned by query (which
+// must take a single object name parameter) and returns the privileges
+// granted to role.
+func readACLPrivileges(conn *sql.DB, query, objectName, role string) ([]string, error) {
+	var acl []string
+	err := conn.QueryRow(query, objectName).Scan(pq.Array(&acl))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range acl {
+		grantee, privileges, _ := decodeACLItem(item)
+		if granteeMatchesRole(grantee, role) {
+			return privileges, nil
+		}
+	}
+	return nil, nil
+}
+
+// granteeQueryValue translates the literal "public" (case-insensitive) into
+// the "PUBLIC" grantee text that information_schema views use, leaving any
+// other role name untouched.
+func granteeQueryValue(role string) string {
+	if strings.EqualFold(role, "public") {
+		return "PUBLIC"
+	}
+	return role
+}
+
+// granteeMatchesRole compares an aclitem's grantee (empty string for
+// PUBLIC) against a role name that may itself be the literal "public".
+func granteeMatchesRole(grantee, role string) bool {
+	if strings.EqualFold(role, "public") {
+		return grantee == ""
+	}
+	return grantee == role
+}
+
+// readACLPrivilegesForObjects is readACLPrivileges for a cluster-wide object
+// type that is always addressed through the objects attribute. It returns
+// the privileges common to every named object, so that a privilege dropped
+// from any one of them (not just the first) shows up as drift.
+func readACLPrivilegesForObjects(conn *sql.DB, query string, objects []string, role string) ([]string, error) {
+	if len(objects) == 0 {
+		return nil, nil
+	}
+
+	sets := make([][]string, 0, len(objects))
+	for _, object := range objects {
+		privileges, err := readACLPrivileges(conn, query, object, role)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, privileges)
+	}
+	return intersectPrivileges(sets), nil
+}
+
+// inClause appends objects to args and returns a "$3, $4, ..." placeholder
+// list that continues numbering after the existing args.
+func inClause(args []interface{}, objects []string) (string, []interface{}) {
+	placeholders := make([]string, len(objects))
+	for i, object := range objects {
+		args = append(args, object)
+		placeholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+	return strings.Join(placeholders, ", "), args
+}
+
+func queryGrantedPrivileges(conn *sql.DB, query string, args ...interface{}) ([]string, error) {
+	rows, err := conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	privileges := []string{}
+	for rows.Next() {
+		var privilege string
+		if err := rows.Scan(&privilege); err != nil {
+			return nil, err
+		}
+		privileges = append(privileges, privilege)
+	}
+
+	return privileges, rows.Err()
+}
+
+func generateGrantID(d *schema.ResourceData) string {
+	parts := []string{
+		d.Get("database").(string),
+		primaryRole(d),
+		d.Get("object_type").(string),
+	}
+	if v, ok := d.GetOk("schema"); ok {
+		parts = append(parts, v.(string))
+	}
+	return strings.Join(parts, "_")
+}
@@ -3,6 +3,8 @@ package postgresql
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
@@ -66,6 +68,113 @@ func TestCreateGrantQuery(t *testing.T) {
 			privileges: []string{"ALL PRIVILEGES"},
 			expected:   fmt.Sprintf("GRANT ALL PRIVILEGES ON DATABASE %s TO %s WITH GRANT OPTION", pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier(roleName)),
 		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "table",
+				"schema":      databaseName,
+				"role":        roleName,
+				"objects":     []interface{}{"t1", "t2"},
+			}),
+			privileges: []string{"SELECT"},
+			expected: fmt.Sprintf(
+				"GRANT SELECT ON TABLE %s.%s, %s.%s TO %s",
+				pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier("t1"),
+				pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier("t2"),
+				pq.QuoteIdentifier(roleName),
+			),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "function",
+				"schema":      databaseName,
+				"role":        roleName,
+				"objects":     []interface{}{"myfunc"},
+			}),
+			privileges: []string{"EXECUTE"},
+			expected: fmt.Sprintf(
+				"GRANT EXECUTE ON FUNCTION %s.%s TO %s",
+				pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier("myfunc"), pq.QuoteIdentifier(roleName),
+			),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "procedure",
+				"schema":      databaseName,
+				"role":        roleName,
+				"objects":     []interface{}{"myproc"},
+			}),
+			privileges: []string{"EXECUTE"},
+			expected: fmt.Sprintf(
+				"GRANT EXECUTE ON PROCEDURE %s.%s TO %s",
+				pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier("myproc"), pq.QuoteIdentifier(roleName),
+			),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "schema",
+				"schema":      databaseName,
+				"role":        roleName,
+			}),
+			privileges: []string{"USAGE"},
+			expected:   fmt.Sprintf("GRANT USAGE ON SCHEMA %s TO %s", pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier(roleName)),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "foreign_data_wrapper",
+				"role":        roleName,
+				"objects":     []interface{}{"fdw1"},
+			}),
+			privileges: []string{"USAGE"},
+			expected:   fmt.Sprintf("GRANT USAGE ON FOREIGN DATA WRAPPER %s TO %s", pq.QuoteIdentifier("fdw1"), pq.QuoteIdentifier(roleName)),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "foreign_server",
+				"role":        roleName,
+				"objects":     []interface{}{"srv1"},
+			}),
+			privileges: []string{"USAGE"},
+			expected:   fmt.Sprintf("GRANT USAGE ON FOREIGN SERVER %s TO %s", pq.QuoteIdentifier("srv1"), pq.QuoteIdentifier(roleName)),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "language",
+				"role":        roleName,
+				"objects":     []interface{}{"plpgsql"},
+			}),
+			privileges: []string{"USAGE"},
+			expected:   fmt.Sprintf("GRANT USAGE ON LANGUAGE %s TO %s", pq.QuoteIdentifier("plpgsql"), pq.QuoteIdentifier(roleName)),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "database",
+				"database":    databaseName,
+				"role":        "public",
+			}),
+			privileges: []string{"CREATE"},
+			expected:   fmt.Sprintf("GRANT CREATE ON DATABASE %s TO PUBLIC", pq.QuoteIdentifier(databaseName)),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "table",
+				"schema":      databaseName,
+				"role":        roleName,
+				"objects":     []interface{}{"t1"},
+				"columns": []interface{}{
+					map[string]interface{}{
+						"privilege": "SELECT",
+						"columns":   []interface{}{"col2", "col1"},
+					},
+				},
+			}),
+			privileges: []string{"SELECT"},
+			expected: fmt.Sprintf(
+				"GRANT SELECT (%s, %s) ON TABLE %s.%s TO %s",
+				pq.QuoteIdentifier("col1"), pq.QuoteIdentifier("col2"),
+				pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier("t1"),
+				pq.QuoteIdentifier(roleName),
+			),
+		},
 	}
 
 	for _, c := range cases {
@@ -116,6 +225,59 @@ func TestCreateRevokeQuery(t *testing.T) {
 			}),
 			expected: fmt.Sprintf("REVOKE ALL PRIVILEGES ON DATABASE %s FROM %s", pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier(roleName)),
 		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "table",
+				"schema":      databaseName,
+				"role":        roleName,
+				"objects":     []interface{}{"t1"},
+			}),
+			expected: fmt.Sprintf(
+				"REVOKE ALL PRIVILEGES ON TABLE %s.%s FROM %s",
+				pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier("t1"), pq.QuoteIdentifier(roleName),
+			),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "foreign_server",
+				"role":        roleName,
+				"objects":     []interface{}{"srv1"},
+			}),
+			expected: fmt.Sprintf(
+				"REVOKE ALL PRIVILEGES ON FOREIGN SERVER %s FROM %s",
+				pq.QuoteIdentifier("srv1"), pq.QuoteIdentifier(roleName),
+			),
+		},
+		{
+			// "privileges" can shrink on a plain Update without forcing a
+			// recreate even though "columns" (ForceNew) still lists the
+			// dropped privilege: the revoke must still cover every
+			// privilege named in columns, not just what's left in
+			// "privileges".
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "table",
+				"schema":      databaseName,
+				"role":        roleName,
+				"objects":     []interface{}{"t1"},
+				"privileges":  []interface{}{"SELECT"},
+				"columns": []interface{}{
+					map[string]interface{}{
+						"privilege": "SELECT",
+						"columns":   []interface{}{"col1"},
+					},
+					map[string]interface{}{
+						"privilege": "UPDATE",
+						"columns":   []interface{}{"col3"},
+					},
+				},
+			}),
+			expected: fmt.Sprintf(
+				"REVOKE SELECT (%s), UPDATE (%s) ON TABLE %s.%s FROM %s",
+				pq.QuoteIdentifier("col1"), pq.QuoteIdentifier("col3"),
+				pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier("t1"),
+				pq.QuoteIdentifier(roleName),
+			),
+		},
 	}
 
 	for _, c := range cases {
@@ -126,6 +288,112 @@ func TestCreateRevokeQuery(t *testing.T) {
 	}
 }
 
+func TestGranteeList(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+		"object_type": "table",
+		"schema":      "foo",
+		"roles":       []interface{}{"bar", "baz"},
+	})
+
+	out := granteeList(d)
+	for _, want := range []string{pq.QuoteIdentifier("bar"), pq.QuoteIdentifier("baz")} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected grantee list %q to contain %s", out, want)
+		}
+	}
+}
+
+func TestGranteeListPublic(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+		"object_type": "database",
+		"database":    "foo",
+		"roles":       []interface{}{"public"},
+	})
+
+	if out := granteeList(d); out != "PUBLIC" {
+		t.Fatalf("expected PUBLIC, got %q", out)
+	}
+}
+
+func TestIntersectPrivileges(t *testing.T) {
+	cases := []struct {
+		sets     [][]string
+		expected []string
+	}{
+		{
+			sets:     [][]string{{"SELECT", "INSERT"}, {"SELECT", "INSERT"}},
+			expected: []string{"SELECT", "INSERT"},
+		},
+		{
+			// A privilege missing from any one set (e.g. externally revoked
+			// from a non-primary role) drops out of the intersection.
+			sets:     [][]string{{"SELECT", "INSERT"}, {"SELECT"}},
+			expected: []string{"SELECT"},
+		},
+		{
+			sets:     [][]string{{"SELECT"}, {"INSERT"}},
+			expected: nil,
+		},
+	}
+
+	for _, c := range cases {
+		out := intersectPrivileges(c.sets)
+		sort.Strings(out)
+		want := c.expected
+		sort.Strings(want)
+		if len(out) != len(want) {
+			t.Fatalf("intersectPrivileges(%#v) = %#v, want %#v", c.sets, out, want)
+		}
+		for i := range want {
+			if out[i] != want[i] {
+				t.Fatalf("intersectPrivileges(%#v) = %#v, want %#v", c.sets, out, want)
+			}
+		}
+	}
+}
+
+func TestExpandCollapseGrantPrivileges(t *testing.T) {
+	cases := []struct {
+		objectType string
+		expanded   []string
+	}{
+		{objectType: "database", expanded: []string{"CREATE", "TEMPORARY", "CONNECT"}},
+		{objectType: "table", expanded: []string{"SELECT", "INSERT", "UPDATE", "DELETE", "TRUNCATE", "REFERENCES", "TRIGGER"}},
+		{objectType: "sequence", expanded: []string{"USAGE", "SELECT", "UPDATE"}},
+		{objectType: "schema", expanded: []string{"CREATE", "USAGE"}},
+		{objectType: "function", expanded: []string{"EXECUTE"}},
+		{objectType: "procedure", expanded: []string{"EXECUTE"}},
+		{objectType: "foreign_data_wrapper", expanded: []string{"USAGE"}},
+		{objectType: "foreign_server", expanded: []string{"USAGE"}},
+		{objectType: "language", expanded: []string{"USAGE"}},
+	}
+
+	for _, c := range cases {
+		out := expandGrantPrivileges(c.objectType, []string{"ALL PRIVILEGES"})
+		if !reflect.DeepEqual(out, c.expanded) {
+			t.Fatalf("expandGrantPrivileges(%q, ALL PRIVILEGES) = %#v, want %#v", c.objectType, out, c.expanded)
+		}
+
+		collapsed := collapseGrantPrivileges(c.objectType, out)
+		if !reflect.DeepEqual(collapsed, []string{"ALL PRIVILEGES"}) {
+			t.Fatalf("collapseGrantPrivileges(%q, %#v) = %#v, want [\"ALL PRIVILEGES\"]", c.objectType, out, collapsed)
+		}
+	}
+
+	// A privilege list that isn't a single "ALL"/"ALL PRIVILEGES" entry
+	// passes through expandGrantPrivileges untouched...
+	explicit := []string{"SELECT", "INSERT"}
+	if out := expandGrantPrivileges("table", explicit); !reflect.DeepEqual(out, explicit) {
+		t.Fatalf("expandGrantPrivileges(table, %#v) = %#v, want unchanged", explicit, out)
+	}
+
+	// ...and one that's a strict subset of the object type's full expansion
+	// must not collapse back to ALL PRIVILEGES.
+	if out := collapseGrantPrivileges("table", []string{"SELECT", "INSERT"}); !reflect.DeepEqual(out, []string{"SELECT", "INSERT"}) {
+		t.Fatalf("collapseGrantPrivileges(table, partial set) = %#v, want unchanged", out)
+	}
+}
+
 func TestAccPostgresqlGrant(t *testing.T) {
 	skipIfNotAcc(t)
 